@@ -0,0 +1,96 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/launcher"
+	"github.com/go-rod/rod/lib/proto"
+)
+
+// browserPool 维护一个共享的无头 Chrome 实例，用信号量把并发抓取的标签页数量限制在固定上限内，
+// 避免像旧版本那样每次轮询都重新 launcher.New() 启动一个全新的浏览器进程。浏览器由所有
+// target 共享，一旦它崩溃或断开，acquirePage 会重新启动一个新实例；打开标签页的错误以 error
+// 返回，但重新启动本身失败时（MustLaunch/MustConnect）仍会 panic
+type browserPool struct {
+	chromePath string
+	tabs       chan struct{}
+
+	mu      sync.Mutex
+	browser *rod.Browser
+}
+
+// newBrowserPool 启动一个无头 Chrome 实例，最多允许 size 个标签页并发工作
+func newBrowserPool(chromePath string, size int) *browserPool {
+	p := &browserPool{chromePath: chromePath, tabs: make(chan struct{}, size)}
+	p.browser = launchBrowser(chromePath)
+	return p
+}
+
+// launchBrowser 启动一个新的无头 Chrome 实例并与之建立连接
+func launchBrowser(chromePath string) *rod.Browser {
+	u := launcher.New().
+		Leakless(true).
+		Set("disable-gpu", "true").
+		Set("ignore-certificate-errors", "true").
+		Set("ignore-certificate-errors", "1").
+		Set("disable-crash-reporter", "true").
+		Set("disable-notifications", "true").
+		Set("hide-scrollbars", "true").
+		Set("window-size", fmt.Sprintf("%d,%d", 1080, 1920)).
+		Set("mute-audio", "true").
+		Set("incognito", "true").
+		Bin(chromePath).
+		NoSandbox(true).
+		Headless(true).
+		MustLaunch()
+	return rod.New().ControlURL(u).MustConnect()
+}
+
+// current 返回当前持有的共享浏览器实例
+func (p *browserPool) current() *rod.Browser {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.browser
+}
+
+// relaunch 关闭（如果还能关闭）已失联的共享浏览器，并启动一个新实例替换它
+func (p *browserPool) relaunch(dead *rod.Browser) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.browser != dead {
+		// 已经被另一个 goroutine 替换过了
+		return
+	}
+	_ = dead.Close()
+	p.browser = launchBrowser(p.chromePath)
+}
+
+// acquirePage 占用一个并发配额并打开一个新标签页。如果共享浏览器已经崩溃或断开，
+// 会尝试重新启动一次再重试，打开标签页失败时返回 error；重新启动浏览器失败时会 panic
+func (p *browserPool) acquirePage() (*rod.Page, error) {
+	p.tabs <- struct{}{}
+	browser := p.current()
+	page, err := browser.Page(proto.TargetCreateTarget{})
+	if err != nil {
+		p.relaunch(browser)
+		page, err = p.current().Page(proto.TargetCreateTarget{})
+	}
+	if err != nil {
+		<-p.tabs
+		return nil, err
+	}
+	return page, nil
+}
+
+// releasePage 关闭标签页并归还并发配额
+func (p *browserPool) releasePage(page *rod.Page) {
+	_ = page.Close()
+	<-p.tabs
+}
+
+// close 关闭整个共享浏览器实例
+func (p *browserPool) close() {
+	_ = p.current().Close()
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+)
+
+// artifactsDir 是截图与网络日志制品的根目录
+const artifactsDir = "artifacts"
+
+// slowResourceCount 是通知摘要里展示的最慢子资源数量
+const slowResourceCount = 5
+
+// resourceEntry 记录一次页面加载过程中单个子资源请求的关键信息
+type resourceEntry struct {
+	URL      string        `json:"url"`
+	Method   string        `json:"method"`
+	Status   int           `json:"status"`
+	Size     int           `json:"size_bytes"`
+	Duration time.Duration `json:"duration_ms"`
+}
+
+// MarshalJSON 把 Duration 以毫秒数写入 duration_ms 字段
+func (e resourceEntry) MarshalJSON() ([]byte, error) {
+	type alias resourceEntry
+	return json.Marshal(struct {
+		alias
+		Duration int64 `json:"duration_ms"`
+	}{alias(e), e.Duration.Milliseconds()})
+}
+
+// harLog 是简化版的 HAR 风格网络日志，记录一次页面加载期间观察到的所有子资源请求
+type harLog struct {
+	URL       string          `json:"url"`
+	Timestamp time.Time       `json:"timestamp"`
+	Entries   []resourceEntry `json:"entries"`
+}
+
+// inflightRequest 记录一个尚未收到 loadingFinished 事件的请求的已知信息
+type inflightRequest struct {
+	url    string
+	method string
+	status int
+	start  time.Time
+}
+
+// networkRecorder 通过订阅 Network 域的 CDP 事件被动旁观页面加载期间的子资源请求，
+// 不拦截、不代为转发任何请求，因此不会改变浏览器实际观察到的网络行为（cookie、
+// TLS 指纹、重定向链等均与真实访问一致）。entries/inflight 会被事件所在的
+// goroutine 写入、被巡检 goroutine 读取，因此必须加锁保护
+type networkRecorder struct {
+	cancel func()
+	done   chan struct{}
+
+	mu       sync.Mutex
+	inflight map[proto.NetworkRequestID]inflightRequest
+	entries  []resourceEntry
+}
+
+// startNetworkRecorder 为页面订阅 Network 事件，开始被动记录子资源请求
+func startNetworkRecorder(page *rod.Page) *networkRecorder {
+	cancelPage, cancel := page.WithCancel()
+	rec := &networkRecorder{
+		cancel:   cancel,
+		done:     make(chan struct{}),
+		inflight: map[proto.NetworkRequestID]inflightRequest{},
+	}
+
+	wait := cancelPage.EachEvent(
+		func(e *proto.NetworkRequestWillBeSent) {
+			rec.mu.Lock()
+			rec.inflight[e.RequestID] = inflightRequest{
+				url:    e.Request.URL,
+				method: e.Request.Method,
+				start:  time.Now(),
+			}
+			rec.mu.Unlock()
+		},
+		func(e *proto.NetworkResponseReceived) {
+			rec.mu.Lock()
+			if req, ok := rec.inflight[e.RequestID]; ok {
+				req.status = e.Response.Status
+				rec.inflight[e.RequestID] = req
+			}
+			rec.mu.Unlock()
+		},
+		func(e *proto.NetworkLoadingFinished) {
+			rec.mu.Lock()
+			if req, ok := rec.inflight[e.RequestID]; ok {
+				rec.entries = append(rec.entries, resourceEntry{
+					URL:      req.url,
+					Method:   req.method,
+					Status:   req.status,
+					Size:     int(e.EncodedDataLength),
+					Duration: time.Since(req.start),
+				})
+				delete(rec.inflight, e.RequestID)
+			}
+			rec.mu.Unlock()
+		},
+	)
+	go func() {
+		wait()
+		close(rec.done)
+	}()
+	return rec
+}
+
+// stop 取消事件订阅并等待订阅 goroutine 退出。调用方应在 stop 返回后再读取 entries
+// 快照，以免与事件 goroutine 的写入发生竞争
+func (r *networkRecorder) stop() {
+	r.cancel()
+	<-r.done
+}
+
+// snapshot 返回目前记录到的子资源列表的一份拷贝，供 stop 之后安全读取
+func (r *networkRecorder) snapshot() []resourceEntry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]resourceEntry(nil), r.entries...)
+}
+
+// slowest 按耗时从高到低返回最多 n 条子资源记录，用于通知里的摘要
+func (r *networkRecorder) slowest(entries []resourceEntry, n int) []resourceEntry {
+	sorted := append([]resourceEntry(nil), entries...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Duration > sorted[j].Duration })
+	if len(sorted) > n {
+		sorted = sorted[:n]
+	}
+	return sorted
+}
+
+// urlArtifactDir 返回某个 URL 对应的制品目录，按其 URL 的 sha256 摘要分桶，避免特殊字符污染路径
+func urlArtifactDir(url string) string {
+	sum := sha256.Sum256([]byte(url))
+	return filepath.Join(artifactsDir, hex.EncodeToString(sum[:])[:16])
+}
+
+// captureArtifacts 保存一张全页截图和一份 HAR 风格的网络日志，返回二者的本地文件路径（以 artifacts 根目录开头）
+func captureArtifacts(page *rod.Page, url string, entries []resourceEntry, ts time.Time) (pngPath string, harPath string, err error) {
+	dir := urlArtifactDir(url)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", "", err
+	}
+	stamp := ts.Format("20060102T150405.000")
+
+	var png []byte
+	if tryErr := rod.Try(func() {
+		png = page.MustScreenshotFullPage()
+	}); tryErr != nil {
+		return "", "", tryErr
+	}
+	pngPath = filepath.Join(dir, stamp+".png")
+	if err := os.WriteFile(pngPath, png, 0644); err != nil {
+		return "", "", err
+	}
+
+	harBytes, err := json.MarshalIndent(harLog{URL: url, Timestamp: ts, Entries: entries}, "", "  ")
+	if err != nil {
+		return "", "", err
+	}
+	harPath = filepath.Join(dir, stamp+".har")
+	if err := os.WriteFile(harPath, harBytes, 0644); err != nil {
+		return "", "", err
+	}
+	return pngPath, harPath, nil
+}
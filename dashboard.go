@@ -0,0 +1,184 @@
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"log"
+	"net"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+//go:embed web/static
+var dashboardAssets embed.FS
+
+// Dashboard 存储仪表盘 HTTP 服务的配置信息
+type Dashboard struct {
+	Addr      string `yaml:"addr"`       // 默认 :8080
+	PublicURL string `yaml:"public_url"` // 对外可访问的地址前缀，默认根据 addr 拼出 http://<host>:<port>，host 为空时用 localhost
+}
+
+// artifactSecret 用于给 /artifacts 下的截图、网络日志链接签名，避免制品目录被任意遍历访问
+var artifactSecret = newArtifactSecret()
+
+// newArtifactSecret 为本次进程生成一个随机密钥
+func newArtifactSecret() []byte {
+	b := make([]byte, 32)
+	_, _ = rand.Read(b)
+	return b
+}
+
+// signArtifactPath 对制品相对路径签名
+func signArtifactPath(relPath string) string {
+	mac := hmac.New(sha256.New, artifactSecret)
+	mac.Write([]byte(relPath))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// artifactLink 把 captureArtifacts 返回的本地文件路径转换成仪表盘对外提供的带签名访问链接
+func artifactLink(conf Dashboard, localPath string) string {
+	rel := filepath.ToSlash(strings.TrimPrefix(localPath, artifactsDir+string(filepath.Separator)))
+	base := conf.PublicURL
+	if base == "" {
+		addr := conf.Addr
+		if addr == "" {
+			addr = defaultDashboardAddr
+		}
+		base = "http://" + localBaseHost(addr)
+	}
+	return base + "/artifacts/" + rel + "?sig=" + signArtifactPath(rel)
+}
+
+// localBaseHost 把 addr（形如 ":8080" 或 "0.0.0.0:8080"）转换成拼接默认 PublicURL 所用的
+// host:port，空主机名（包括裸 ":port" 写法）一律替换成 localhost
+func localBaseHost(addr string) string {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return "localhost" + addr
+	}
+	if host == "" {
+		host = "localhost"
+	}
+	return fmt.Sprintf("%s:%s", host, port)
+}
+
+// defaultDashboardAddr 是未配置 dashboard.addr 时的默认监听地址
+const defaultDashboardAddr = ":8080"
+
+// dashboardServer 承载仪表盘的静态页面、REST API 和 WebSocket 推送
+type dashboardServer struct {
+	addr    string
+	status  *statusStore
+	metrics *metricsRegistry
+	hub     *wsHub
+	checks  *checkStore
+}
+
+// newDashboardServer 创建一个仪表盘服务，尚未开始监听
+func newDashboardServer(conf Dashboard, status *statusStore, metrics *metricsRegistry, hub *wsHub, checks *checkStore) *dashboardServer {
+	addr := conf.Addr
+	if addr == "" {
+		addr = defaultDashboardAddr
+	}
+	return &dashboardServer{addr: addr, status: status, metrics: metrics, hub: hub, checks: checks}
+}
+
+// start 在后台 goroutine 里启动 HTTP 监听
+func (d *dashboardServer) start() {
+	staticFS, err := fs.Sub(dashboardAssets, "web/static")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/", http.FileServer(http.FS(staticFS)))
+	mux.HandleFunc("/api/targets", d.handleTargets)
+	mux.HandleFunc("/api/history", d.handleHistory)
+	mux.HandleFunc("/api/stats", d.handleStats)
+	mux.HandleFunc("/ws", d.hub.serveWS)
+	mux.HandleFunc("/metrics", d.handleMetrics)
+	mux.HandleFunc("/artifacts/", d.handleArtifact)
+
+	go func() {
+		log.Println("dashboard: 监听于 " + d.addr)
+		if err := http.ListenAndServe(d.addr, mux); err != nil {
+			log.Println("dashboard: " + err.Error())
+		}
+	}()
+}
+
+// handleTargets 返回当前所有被监测 URL 的状态快照
+func (d *dashboardServer) handleTargets(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(d.status.list())
+}
+
+// handleHistory 返回单个 URL 的延迟历史采样，通过 ?url= 指定
+func (d *dashboardServer) handleHistory(w http.ResponseWriter, r *http.Request) {
+	url := r.URL.Query().Get("url")
+	t, ok := d.status.get(url)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(t.History)
+}
+
+// handleMetrics 以 Prometheus 文本格式输出当前指标
+func (d *dashboardServer) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	d.metrics.render(w, d.status)
+}
+
+// handleArtifact 对外提供故障截图和网络日志，要求 URL 携带与相对路径匹配的 sig 参数，
+// 防止制品目录被任意遍历访问
+func (d *dashboardServer) handleArtifact(w http.ResponseWriter, r *http.Request) {
+	rel := filepath.Clean(strings.TrimPrefix(r.URL.Path, "/artifacts/"))
+	if rel == "." || strings.HasPrefix(rel, "..") {
+		http.NotFound(w, r)
+		return
+	}
+	if !hmac.Equal([]byte(signArtifactPath(filepath.ToSlash(rel))), []byte(r.URL.Query().Get("sig"))) {
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return
+	}
+	http.ServeFile(w, r, filepath.Join(artifactsDir, rel))
+}
+
+// defaultStatsWindow 是 /api/stats 未指定 window 参数时回看的时间范围
+const defaultStatsWindow = 24 * time.Hour
+
+// handleStats 返回某个 URL 在指定时间窗口内的可用率、延迟分位数和 MTTR，
+// 通过 ?url= 指定目标，?window= 指定回看时长（如 "24h"、"7h"），默认 24 小时
+func (d *dashboardServer) handleStats(w http.ResponseWriter, r *http.Request) {
+	url := r.URL.Query().Get("url")
+	if url == "" {
+		http.Error(w, "missing url", http.StatusBadRequest)
+		return
+	}
+	window := defaultStatsWindow
+	if raw := r.URL.Query().Get("window"); raw != "" {
+		parsed, err := time.ParseDuration(raw)
+		if err != nil {
+			http.Error(w, "invalid window: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		window = parsed
+	}
+	result, err := d.checks.stats(url, time.Now().Add(-window))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestHandleArtifactRejectsTraversalAndBadSignature(t *testing.T) {
+	dir := t.TempDir()
+	orig, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(orig)
+
+	bucket := filepath.Join(artifactsDir, "abc123")
+	if err := os.MkdirAll(bucket, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(bucket, "shot.png"), []byte("fake-png"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	// secret 存在 artifactsDir 之外，遍历成功就能读到它
+	if err := os.WriteFile("secret.txt", []byte("top-secret"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	d := &dashboardServer{}
+
+	rel := "abc123/shot.png"
+
+	cases := []struct {
+		name       string
+		path       string
+		sig        string
+		wantStatus int
+	}{
+		{"valid signature serves the file", "/artifacts/" + rel, signArtifactPath(rel), 200},
+		{"wrong signature is forbidden", "/artifacts/" + rel, "deadbeef", 403},
+		{"missing signature is forbidden", "/artifacts/" + rel, "", 403},
+		{"path traversal is rejected before signature check", "/artifacts/../secret.txt", signArtifactPath("../secret.txt"), 404},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", c.path+"?sig="+c.sig, nil)
+			w := httptest.NewRecorder()
+			d.handleArtifact(w, req)
+			if w.Code != c.wantStatus {
+				t.Fatalf("status = %d, want %d (body: %s)", w.Code, c.wantStatus, w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"io"
+	"log"
+	"time"
+
+	rotatelogs "github.com/lestrrat-go/file-rotatelogs"
+	"github.com/sirupsen/logrus"
+)
+
+// Logging 存储运行日志滚动策略的配置信息
+type Logging struct {
+	Dir      string `yaml:"dir"`      // 日志目录，默认当前目录
+	MaxAge   int    `yaml:"max_age"`  // 日志最长保留天数，默认 30
+	Rotation int    `yaml:"rotation"` // 单个日志文件按多少天滚动一次，默认 1（按天滚动）
+}
+
+const (
+	defaultLogDir      = "."
+	defaultLogMaxAge   = 30
+	defaultLogRotation = 1
+)
+
+// newRotatingWriter 构造一个按 Rotation 天数滚动、最长保留 MaxAge 天的日志写入器，
+// 替代旧版本一直追加写入的 app.log
+func newRotatingWriter(conf Logging) io.WriteCloser {
+	dir := conf.Dir
+	if dir == "" {
+		dir = defaultLogDir
+	}
+	maxAge := conf.MaxAge
+	if maxAge <= 0 {
+		maxAge = defaultLogMaxAge
+	}
+	rotation := conf.Rotation
+	if rotation <= 0 {
+		rotation = defaultLogRotation
+	}
+	w, err := rotatelogs.New(
+		dir+"/app.log.%Y%m%d",
+		rotatelogs.WithLinkName(dir+"/app.log"),
+		rotatelogs.WithMaxAge(time.Duration(maxAge)*24*time.Hour),
+		rotatelogs.WithRotationTime(time.Duration(rotation)*24*time.Hour),
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return w
+}
+
+// newCheckLogger 构造一个输出 JSON 结构化日志的 logger，专门记录每次巡检的结果
+func newCheckLogger(w io.Writer) *logrus.Logger {
+	logger := logrus.New()
+	logger.SetFormatter(&logrus.JSONFormatter{TimestampFormat: time.RFC3339})
+	logger.SetOutput(w)
+	return logger
+}
+
+// logCheckResult 以结构化字段 (url、duration_ms、status、error、ts) 记录一次巡检结果
+func logCheckResult(logger *logrus.Logger, url string, duration time.Duration, status statusKind, errMsg string) {
+	logger.WithFields(logrus.Fields{
+		"url":         url,
+		"duration_ms": duration.Milliseconds(),
+		"status":      string(status),
+		"error":       errMsg,
+		"ts":          time.Now().Format(time.RFC3339),
+	}).Info("check")
+}
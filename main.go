@@ -1,21 +1,17 @@
 package main
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
 	"errors"
-	"fmt"
-	"github.com/go-rod/rod"
-	"github.com/go-rod/rod/lib/launcher"
-	"gopkg.in/yaml.v3"
+	"flag"
 	"io"
-	"io/ioutil"
 	"log"
-	"net/http"
 	"os"
-	"strconv"
+	"os/signal"
 	"time"
+
+	"github.com/go-rod/rod"
+	"gopkg.in/yaml.v3"
 )
 
 // ConfYaml 用于解析 YAML 配置文件中的配置部分
@@ -25,38 +21,54 @@ type ConfYaml struct {
 
 // Config 存储配置信息，如 URL、超时时间、轮询间隔、Chrome路径、推送配置
 type Config struct {
-	Url      []string `yaml:"url"`
-	Timeout  int      `yaml:"timeout"`
-	Polling  int      `yaml:"polling"`
-	Chrome   string   `yaml:"chrome"`
-	Pushplus Pushplus `yaml:"pushplus"`
+	Url       []URLEntry           `yaml:"url"`
+	Targets   []Target             `yaml:"targets"`
+	Timeout   int                  `yaml:"timeout"`
+	Polling   int                  `yaml:"polling"`
+	Chrome    string               `yaml:"chrome"`
+	Workers   int                  `yaml:"workers"`
+	Pushplus  Pushplus             `yaml:"pushplus"`
+	Notifiers map[string]yaml.Node `yaml:"notifiers"`
+	Dashboard Dashboard            `yaml:"dashboard"`
+	Logging   Logging              `yaml:"logging"`
+	Storage   Storage              `yaml:"storage"`
 }
 
-// Pushplus 存储推送配置，包含Token、标题、群组
-type Pushplus struct {
-	Token string `yaml:"token"`
-	Title string `yaml:"title"`
-	Topic int    `yaml:"topic"`
-}
+// defaultWorkers 是未配置 workers 时，浏览器池允许的默认并发标签页数量
+const defaultWorkers = 4
 
-// pushRequest 是用于发送推送通知的结构体，包含 Token、标题、内容 、模版、群组
-type pushRequest struct {
-	Token    string `json:"token"`
-	Title    string `json:"title"`
-	Content  string `json:"content"`
-	Template string `json:"template"`
-	Topic    int    `json:"topic"`
+// URLEntry 描述一个被监测的 URL，以及内容比对所需的可选过滤规则。
+// 配置文件中既可以直接写一个 URL 字符串，也可以写成带 selector/regex 的对象。
+type URLEntry struct {
+	URL          string   `yaml:"url"`
+	Selector     string   `yaml:"selector"`
+	IncludeRegex string   `yaml:"include_regex"`
+	ExcludeRegex string   `yaml:"exclude_regex"`
+	Notifiers    []string `yaml:"notifiers"`
 }
 
-// pushResponse 存储推送服务的响应结果，如状态码、消息
-type pushResponse struct {
-	Code int    `json:"code"`
-	Msg  string `json:"msg"`
+// UnmarshalYAML 兼容两种写法：纯字符串 URL，或携带过滤规则的对象
+func (e *URLEntry) UnmarshalYAML(value *yaml.Node) error {
+	var plain string
+	if err := value.Decode(&plain); err == nil {
+		e.URL = plain
+		return nil
+	}
+	type rawURLEntry URLEntry
+	var raw rawURLEntry
+	if err := value.Decode(&raw); err != nil {
+		return err
+	}
+	*e = URLEntry(raw)
+	return nil
 }
 
 // 全局变量 config 用于存储配置信息
 var config Config
 
+// resetSnapshots 控制是否在启动时清空已保存的页面内容指纹
+var resetSnapshots = flag.Bool("reset", false, "清空已保存的页面内容指纹后退出")
+
 // parseYaml 用于解析 YAML 配置文件并返回配置部分
 func parseYaml(file string) Config {
 	config := new(ConfYaml)
@@ -71,129 +83,131 @@ func parseYaml(file string) Config {
 	return config.Config
 }
 
-// 初始化函数，用于读取配置文件
-func init() {
-	config = parseYaml("conf.yaml")
-}
-
 // 主函数，程序的入口点
 func main() {
-	// 设置日志文件和输出
-	logFile, err := os.OpenFile("app.log", os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	flag.Parse()
+	config = parseYaml("conf.yaml")
+	// 设置按天滚动的日志输出
+	rotatingLog := newRotatingWriter(config.Logging)
+	defer rotatingLog.Close()
+	log.SetOutput(io.MultiWriter(os.Stdout, rotatingLog))
+	checkLogger := newCheckLogger(rotatingLog)
+
+	store, err := openContentStore(snapshotDBFile)
 	if err != nil {
 		log.Fatal(err)
 	}
-	defer logFile.Close()
-	mw := io.MultiWriter(os.Stdout, logFile)
-	log.SetOutput(mw)
-	// 启动定时任务
-	tickerTask()
-}
+	defer store.close()
 
-// pushPlusNotify 用于发送推送通知到 Pushplus 服务
-func pushPlusNotify(msg string) error {
-	httpClient := &http.Client{}
-	url := "http://www.pushplus.plus/send"
-	title := config.Pushplus.Title
-	token := config.Pushplus.Token
-	topic := config.Pushplus.Topic
-	data := pushRequest{Token: token, Title: title, Content: msg, Template: "html", Topic: topic}
-	reqBody, err := json.Marshal(data)
-	if err != nil {
-		return err
-	}
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(reqBody))
+	checks, err := openCheckStore(config.Storage)
 	if err != nil {
-		return err
-	}
-	req.Header.Set("Content-Type", "application/json")
-	resp, err := httpClient.Do(req)
-	if err != nil {
-		return err
+		log.Fatal(err)
 	}
-	defer resp.Body.Close()
-	bodyText, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return err
+	defer checks.close()
+
+	if *resetSnapshots {
+		if err := store.reset(); err != nil {
+			log.Fatal(err)
+		}
+		log.Println("snapshot: 已清空所有页面内容指纹")
+		return
 	}
-	log.Println("pushplus: " + string(bodyText))
-	var push pushResponse
-	err = json.Unmarshal(bodyText, &push)
+
+	targets := resolveTargets(config)
+
+	urlNotifiers, err := resolveNotifiers(config, targets)
 	if err != nil {
-		return err
-	}
-	if push.Code != 200 {
-		return errors.New(push.Msg)
+		log.Fatal(err)
 	}
-	return nil
-}
 
-// tickerTask 启动定时任务，定期检查网站状态
-func tickerTask() {
-	ticker := time.NewTicker(time.Duration(config.Polling) * time.Second)
-	for {
-		select {
-		case <-ticker.C:
-			// 遍历配置的 URL，检查每个网站的状态
-			for _, url := range config.Url {
-				duration, err := pageMonitor(url)
-				log.Println("pageMonitor: " + url + " " + strconv.FormatFloat(duration.Seconds(), 'f', 2, 64) + "s")
-				// 根据检查结果发送推送通知
-				if err != nil {
-					err := pushPlusNotify("<b>通知:</b> " + url + " <strong>网站无法访问!</strong>" + "</br>" + "<b>事件时间:</b> " + time.Now().Format("2006-01-02 15:04:05") + "</br>" + "<b>错误代码:</b> " + err.Error())
-					if err != nil {
-						log.Println(err)
-					}
-				}
-				if duration.Seconds() > float64(config.Timeout) {
-					err := pushPlusNotify("<b>通知:</b> " + url + " <strong>网站超时访问!</strong>" + "</br>" + "<b>事件时间:</b> " + time.Now().Format("2006-01-02 15:04:05") + "</br>" + "<b>错误代码:</b> " + "访问时间" + strconv.FormatFloat(duration.Seconds(), 'f', 2, 64) + "s")
-					if err != nil {
-						log.Println(err)
-					}
-				}
-			}
-		}
+	workers := config.Workers
+	if workers <= 0 {
+		workers = defaultWorkers
 	}
+	pool := newBrowserPool(config.Chrome, workers)
+	defer pool.close()
+
+	status := newStatusStore()
+	metrics := newMetricsRegistry()
+	hub := newWSHub()
+	dashboard := newDashboardServer(config.Dashboard, status, metrics, hub, checks)
+	dashboard.start()
+
+	// 按各自的 schedule 启动定时任务
+	c := startScheduler(targets, store, pool, urlNotifiers, status, metrics, hub, checks, checkLogger)
+	defer c.Stop()
 
+	// 阻塞主 goroutine，直到收到退出信号
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt)
+	<-stop
 }
 
-// pageMonitor 用于监测一个页面的加载时间，返回加载时间和错误信息
-func pageMonitor(url string) (time.Duration, error) {
+// monitorResult 汇总一次巡检的全部产出：加载时间、内容比对结果，以及故障或内容变化时
+// 留存的截图与网络日志
+type monitorResult struct {
+	Duration      time.Duration
+	Changed       bool
+	Diff          string
+	ContentHash   string
+	Screenshot    string
+	SlowResources []resourceEntry
+	Err           error
+}
+
+// pageMonitor 用于监测一个页面的加载时间与内容变化。访问失败、超时或内容发生变化时，
+// 会额外保存一张全页截图和一份 HAR 风格的网络日志，供通知渠道和仪表盘回溯问题
+func pageMonitor(target Target, store *contentStore, pool *browserPool) monitorResult {
 	start := time.Now()
-	// 配置并启动一个无头 Chrome 实例
-	u := launcher.New().
-		Leakless(true).
-		Set("disable-gpu", "true").
-		Set("ignore-certificate-errors", "true").
-		Set("ignore-certificate-errors", "1").
-		Set("disable-crash-reporter", "true").
-		Set("disable-notifications", "true").
-		Set("hide-scrollbars", "true").
-		Set("window-size", fmt.Sprintf("%d,%d", 1080, 1920)).
-		Set("mute-audio", "true").
-		Set("incognito", "true").
-		Bin(config.Chrome).
-		NoSandbox(true).
-		Headless(true).
-		MustLaunch()
-	browser := rod.New().ControlURL(u).MustConnect()
-	defer browser.MustClose()
-	page := browser.MustPage()
-	err := rod.Try(func() {
-		page.Timeout(20 * time.Second).MustNavigate(url).MustWaitLoad()
+	page, err := pool.acquirePage()
+	if err != nil {
+		return monitorResult{Err: err}
+	}
+	defer pool.releasePage(page)
+
+	entry := target.urlEntry()
+	recorder := startNetworkRecorder(page)
+	defer recorder.stop()
+
+	timeoutPage := page.Timeout(20 * time.Second)
+	err = rod.Try(func() {
+		timeoutPage.MustNavigate(entry.URL).MustWaitLoad()
 	})
-	defer page.MustClose()
 	// 根据监测结果返回加载时间和可能的错误
-	if errors.Is(err, context.DeadlineExceeded) {
-		return 0, errors.New("timeout")
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		err = errors.New("timeout")
+	case errors.Is(err, &rod.NavigationError{Reason: "net::ERR_NAME_NOT_RESOLVED"}):
+		err = errors.New("offline")
+	}
+	if err != nil {
+		return captureOnFailure(page, entry.URL, recorder, monitorResult{Err: err})
+	}
+	duration := time.Since(start)
 
+	changed, diff, contentHash, diffErr := detectContentChange(timeoutPage, entry, store)
+	if diffErr != nil {
+		// 内容比对失败不影响本次的可用性检测结果，仅记录日志
+		log.Println("detectContentChange: " + entry.URL + " " + diffErr.Error())
 	}
-	if errors.Is(err, &rod.NavigationError{Reason: "net::ERR_NAME_NOT_RESOLVED"}) {
-		return 0, errors.New("offline")
+	result := monitorResult{Duration: duration, Changed: changed, Diff: diff, ContentHash: contentHash}
+	if changed || duration.Seconds() > float64(target.alertTimeout()) {
+		result = captureOnFailure(page, entry.URL, recorder, result)
 	}
+	return result
+}
+
+// captureOnFailure 保存截图和网络日志，并把对外可访问的链接与最慢的子资源记录填入 result。
+// 先停止网络事件订阅，再读取 entries 快照，避免与仍在运行的事件 goroutine 并发访问
+func captureOnFailure(page *rod.Page, url string, recorder *networkRecorder, result monitorResult) monitorResult {
+	recorder.stop()
+	entries := recorder.snapshot()
+	pngPath, _, err := captureArtifacts(page, url, entries, time.Now())
 	if err != nil {
-		return 0, err
+		log.Println("captureArtifacts: " + url + " " + err.Error())
+		return result
 	}
-	duration := time.Since(start)
-	return duration, nil
+	result.Screenshot = artifactLink(config.Dashboard, pngPath)
+	result.SlowResources = recorder.slowest(entries, slowResourceCount)
+	return result
 }
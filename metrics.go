@@ -0,0 +1,111 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+)
+
+// latencyBuckets 是延迟直方图的桶上界，单位秒，沿用 Prometheus 客户端库的惯用档位
+var latencyBuckets = []float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// metricsRegistry 在内存中累计 /metrics 端点所需的计数器与直方图样本
+type metricsRegistry struct {
+	mu             sync.Mutex
+	pollCount      map[string]uint64
+	notifyFailures uint64
+	latencySum     map[string]float64
+	latencyCount   map[string]uint64
+	latencyBucket  map[string][]uint64 // 与 latencyBuckets 等长的累计计数
+}
+
+// newMetricsRegistry 创建一个空的指标注册表
+func newMetricsRegistry() *metricsRegistry {
+	return &metricsRegistry{
+		pollCount:     make(map[string]uint64),
+		latencySum:    make(map[string]float64),
+		latencyCount:  make(map[string]uint64),
+		latencyBucket: make(map[string][]uint64),
+	}
+}
+
+// incPoll 记录一次对该 URL 的轮询
+func (m *metricsRegistry) incPoll(url string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.pollCount[url]++
+}
+
+// incNotifyFailure 记录一次通知渠道发送失败（重试耗尽后）
+func (m *metricsRegistry) incNotifyFailure() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.notifyFailures++
+}
+
+// observeLatency 把一次页面加载耗时计入延迟直方图
+func (m *metricsRegistry) observeLatency(url string, seconds float64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.latencySum[url] += seconds
+	m.latencyCount[url]++
+	buckets, ok := m.latencyBucket[url]
+	if !ok {
+		buckets = make([]uint64, len(latencyBuckets))
+		m.latencyBucket[url] = buckets
+	}
+	for i, le := range latencyBuckets {
+		if seconds <= le {
+			buckets[i]++
+		}
+	}
+}
+
+// render 以 Prometheus 文本格式输出所有指标；up 取自当前的状态存储
+func (m *metricsRegistry) render(w io.Writer, status *statusStore) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	fmt.Fprintln(w, "# HELP pagemonitor_up 目标站点当前是否可访问 (1=是, 0=否)")
+	fmt.Fprintln(w, "# TYPE pagemonitor_up gauge")
+	for _, t := range status.list() {
+		up := 0
+		if t.Status == statusUp || t.Status == statusChanged {
+			up = 1
+		}
+		fmt.Fprintf(w, "pagemonitor_up{url=%q} %d\n", t.URL, up)
+	}
+
+	fmt.Fprintln(w, "# HELP pagemonitor_poll_total 对该 URL 执行的轮询总次数")
+	fmt.Fprintln(w, "# TYPE pagemonitor_poll_total counter")
+	for _, url := range sortedKeys(m.pollCount) {
+		fmt.Fprintf(w, "pagemonitor_poll_total{url=%q} %d\n", url, m.pollCount[url])
+	}
+
+	fmt.Fprintln(w, "# HELP pagemonitor_notify_failures_total 通知渠道发送失败（重试耗尽）的总次数")
+	fmt.Fprintln(w, "# TYPE pagemonitor_notify_failures_total counter")
+	fmt.Fprintf(w, "pagemonitor_notify_failures_total %d\n", m.notifyFailures)
+
+	fmt.Fprintln(w, "# HELP pagemonitor_latency_seconds 页面加载耗时")
+	fmt.Fprintln(w, "# TYPE pagemonitor_latency_seconds histogram")
+	for _, url := range sortedKeys(m.latencyCount) {
+		buckets := m.latencyBucket[url]
+		for i, le := range latencyBuckets {
+			fmt.Fprintf(w, "pagemonitor_latency_seconds_bucket{url=%q,le=\"%g\"} %d\n", url, le, buckets[i])
+		}
+		fmt.Fprintf(w, "pagemonitor_latency_seconds_bucket{url=%q,le=\"+Inf\"} %d\n", url, m.latencyCount[url])
+		fmt.Fprintf(w, "pagemonitor_latency_seconds_sum{url=%q} %g\n", url, m.latencySum[url])
+		fmt.Fprintf(w, "pagemonitor_latency_seconds_count{url=%q} %d\n", url, m.latencyCount[url])
+	}
+}
+
+// sortedKeys 返回计数器 map 中按字典序排列的 URL 列表，让每次渲染的 /metrics 输出保持稳定
+func sortedKeys(m map[string]uint64) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Bark 存储 Bark (iOS 推送) 的配置信息
+type Bark struct {
+	Server string `yaml:"server"` // 默认 https://api.day.app
+	Key    string `yaml:"key"`
+}
+
+// barkNotifier 通过 Bark 发送推送通知
+type barkNotifier struct {
+	conf Bark
+}
+
+func init() {
+	registerNotifierType("bark", func(node yaml.Node) (Notifier, error) {
+		var conf Bark
+		if err := node.Decode(&conf); err != nil {
+			return nil, err
+		}
+		if conf.Server == "" {
+			conf.Server = "https://api.day.app"
+		}
+		return &barkNotifier{conf: conf}, nil
+	})
+}
+
+// Notify 把事件以 title/body 的形式 GET 到 Bark 的推送地址
+func (b *barkNotifier) Notify(ctx context.Context, event Event) error {
+	endpoint := fmt.Sprintf("%s/%s/%s/%s", b.conf.Server, b.conf.Key, url.PathEscape(eventTitle(event)), url.PathEscape(eventBody(event)))
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("bark: 推送失败，状态码 %d", resp.StatusCode)
+	}
+	return nil
+}
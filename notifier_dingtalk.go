@@ -0,0 +1,107 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Dingtalk 存储钉钉自定义机器人 webhook 的配置信息
+type Dingtalk struct {
+	Webhook string `yaml:"webhook"`
+	Secret  string `yaml:"secret"` // 可选的加签密钥
+}
+
+// dingtalkPayload 是钉钉自定义机器人支持的文本消息格式
+type dingtalkPayload struct {
+	MsgType string          `json:"msgtype"`
+	Text    dingtalkContent `json:"text"`
+}
+
+type dingtalkContent struct {
+	Content string `json:"content"`
+}
+
+// dingtalkResponse 是钉钉 webhook 的响应结构
+type dingtalkResponse struct {
+	ErrCode int    `json:"errcode"`
+	ErrMsg  string `json:"errmsg"`
+}
+
+// dingtalkNotifier 通过钉钉自定义机器人 webhook 发送通知
+type dingtalkNotifier struct {
+	conf Dingtalk
+}
+
+func init() {
+	registerNotifierType("dingtalk", func(node yaml.Node) (Notifier, error) {
+		var conf Dingtalk
+		if err := node.Decode(&conf); err != nil {
+			return nil, err
+		}
+		return &dingtalkNotifier{conf: conf}, nil
+	})
+}
+
+// dingtalkSignedURL 按钉钉文档要求，用 "timestamp\nsecret" 经 HMAC-SHA256 加签后拼到 webhook 地址上
+func dingtalkSignedURL(webhook, secret string, timestamp int64) (string, error) {
+	stringToSign := fmt.Sprintf("%d\n%s", timestamp, secret)
+	mac := hmac.New(sha256.New, []byte(secret))
+	if _, err := mac.Write([]byte(stringToSign)); err != nil {
+		return "", err
+	}
+	sign := base64.StdEncoding.EncodeToString(mac.Sum(nil))
+	return fmt.Sprintf("%s&timestamp=%d&sign=%s", webhook, timestamp, url.QueryEscape(sign)), nil
+}
+
+// Notify 把事件以文本消息的形式发送到钉钉自定义机器人
+func (d *dingtalkNotifier) Notify(ctx context.Context, event Event) error {
+	endpoint := d.conf.Webhook
+	if d.conf.Secret != "" {
+		signed, err := dingtalkSignedURL(d.conf.Webhook, d.conf.Secret, time.Now().UnixMilli())
+		if err != nil {
+			return err
+		}
+		endpoint = signed
+	}
+	payload := dingtalkPayload{
+		MsgType: "text",
+		Text:    dingtalkContent{Content: eventTitle(event) + "\n" + eventBody(event)},
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewBuffer(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	bodyText, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	var result dingtalkResponse
+	if err := json.Unmarshal(bodyText, &result); err != nil {
+		return err
+	}
+	if result.ErrCode != 0 {
+		return fmt.Errorf("dingtalk: %s", result.ErrMsg)
+	}
+	return nil
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"testing"
+)
+
+func TestDingtalkSignedURL(t *testing.T) {
+	const webhook = "https://oapi.dingtalk.com/robot/send?access_token=xxx"
+	const secret = "SEC000000"
+	const timestamp = int64(1700000000000)
+
+	signed, err := dingtalkSignedURL(webhook, secret, timestamp)
+	if err != nil {
+		t.Fatalf("dingtalkSignedURL返回了错误: %v", err)
+	}
+
+	stringToSign := fmt.Sprintf("%d\n%s", timestamp, secret)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(stringToSign))
+	wantSign := url.QueryEscape(base64.StdEncoding.EncodeToString(mac.Sum(nil)))
+	want := fmt.Sprintf("%s&timestamp=%d&sign=%s", webhook, timestamp, wantSign)
+
+	if signed != want {
+		t.Fatalf("签名后的URL不符合预期:\n got:  %s\n want: %s", signed, want)
+	}
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Feishu 存储飞书自定义机器人 webhook 的配置信息
+type Feishu struct {
+	Webhook string `yaml:"webhook"`
+	Secret  string `yaml:"secret"` // 可选的签名校验密钥
+}
+
+// feishuPayload 是飞书自定义机器人支持的文本消息格式
+type feishuPayload struct {
+	Timestamp string        `json:"timestamp,omitempty"`
+	Sign      string        `json:"sign,omitempty"`
+	MsgType   string        `json:"msg_type"`
+	Content   feishuContent `json:"content"`
+}
+
+type feishuContent struct {
+	Text string `json:"text"`
+}
+
+// feishuResponse 是飞书 webhook 的响应结构
+type feishuResponse struct {
+	Code int    `json:"code"`
+	Msg  string `json:"msg"`
+}
+
+// feishuNotifier 通过飞书自定义机器人 webhook 发送通知
+type feishuNotifier struct {
+	conf Feishu
+}
+
+func init() {
+	registerNotifierType("feishu", func(node yaml.Node) (Notifier, error) {
+		var conf Feishu
+		if err := node.Decode(&conf); err != nil {
+			return nil, err
+		}
+		return &feishuNotifier{conf: conf}, nil
+	})
+}
+
+// feishuSign 按飞书文档要求，以 "timestamp\nsecret" 作为 HMAC-SHA256 的密钥对空串签名
+func feishuSign(secret string, timestamp int64) (string, error) {
+	stringToSign := fmt.Sprintf("%d\n%s", timestamp, secret)
+	mac := hmac.New(sha256.New, []byte(stringToSign))
+	if _, err := mac.Write([]byte{}); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil)), nil
+}
+
+// Notify 把事件以文本消息的形式发送到飞书自定义机器人
+func (f *feishuNotifier) Notify(ctx context.Context, event Event) error {
+	payload := feishuPayload{
+		MsgType: "text",
+		Content: feishuContent{Text: eventTitle(event) + "\n" + eventBody(event)},
+	}
+	if f.conf.Secret != "" {
+		ts := time.Now().Unix()
+		sign, err := feishuSign(f.conf.Secret, ts)
+		if err != nil {
+			return err
+		}
+		payload.Timestamp = strconv.FormatInt(ts, 10)
+		payload.Sign = sign
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", f.conf.Webhook, bytes.NewBuffer(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	bodyText, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	var result feishuResponse
+	if err := json.Unmarshal(bodyText, &result); err != nil {
+		return err
+	}
+	if result.Code != 0 {
+		return fmt.Errorf("feishu: %s", result.Msg)
+	}
+	return nil
+}
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"testing"
+)
+
+func TestFeishuSign(t *testing.T) {
+	const secret = "SECabcdef"
+	const timestamp = int64(1700000000)
+
+	sign, err := feishuSign(secret, timestamp)
+	if err != nil {
+		t.Fatalf("feishuSign返回了错误: %v", err)
+	}
+
+	stringToSign := fmt.Sprintf("%d\n%s", timestamp, secret)
+	mac := hmac.New(sha256.New, []byte(stringToSign))
+	mac.Write([]byte{})
+	want := base64.StdEncoding.EncodeToString(mac.Sum(nil))
+
+	if sign != want {
+		t.Fatalf("签名结果不符合预期: got %s, want %s", sign, want)
+	}
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Pushplus 存储推送配置，包含Token、标题、群组
+type Pushplus struct {
+	Token string `yaml:"token"`
+	Title string `yaml:"title"`
+	Topic int    `yaml:"topic"`
+}
+
+// pushplusRequest 是发送给 PushPlus 的请求体，包含 Token、标题、内容、模版、群组
+type pushplusRequest struct {
+	Token    string `json:"token"`
+	Title    string `json:"title"`
+	Content  string `json:"content"`
+	Template string `json:"template"`
+	Topic    int    `json:"topic"`
+}
+
+// pushplusResponse 存储 PushPlus 的响应结果，如状态码、消息
+type pushplusResponse struct {
+	Code int    `json:"code"`
+	Msg  string `json:"msg"`
+}
+
+// pushplusNotifier 通过 PushPlus 发送通知
+type pushplusNotifier struct {
+	conf Pushplus
+}
+
+func newPushplusNotifier(conf Pushplus) *pushplusNotifier {
+	return &pushplusNotifier{conf: conf}
+}
+
+func init() {
+	registerNotifierType("pushplus", func(node yaml.Node) (Notifier, error) {
+		var conf Pushplus
+		if err := node.Decode(&conf); err != nil {
+			return nil, err
+		}
+		return newPushplusNotifier(conf), nil
+	})
+}
+
+// Notify 用于发送推送通知到 Pushplus 服务
+func (p *pushplusNotifier) Notify(ctx context.Context, event Event) error {
+	httpClient := &http.Client{}
+	url := "http://www.pushplus.plus/send"
+	msg := "<b>通知:</b> " + eventTitle(event) + "</br>" + strings.ReplaceAll(eventBody(event), "\n", "</br>")
+	data := pushplusRequest{Token: p.conf.Token, Title: p.conf.Title, Content: msg, Template: "html", Topic: p.conf.Topic}
+	reqBody, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(reqBody))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	bodyText, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	var push pushplusResponse
+	if err := json.Unmarshal(bodyText, &push); err != nil {
+		return err
+	}
+	if push.Code != 200 {
+		return errors.New(push.Msg)
+	}
+	return nil
+}
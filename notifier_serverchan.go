@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ServerChan 存储 Server 酱 (sct.ftqq.com) 的配置信息
+type ServerChan struct {
+	SendKey string `yaml:"send_key"`
+}
+
+// serverChanResponse 是 Server 酱 接口的响应结构
+type serverChanResponse struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// serverChanNotifier 通过 Server 酱 发送推送通知
+type serverChanNotifier struct {
+	conf ServerChan
+}
+
+func init() {
+	registerNotifierType("serverchan", func(node yaml.Node) (Notifier, error) {
+		var conf ServerChan
+		if err := node.Decode(&conf); err != nil {
+			return nil, err
+		}
+		return &serverChanNotifier{conf: conf}, nil
+	})
+}
+
+// Notify 把事件推送到 Server 酱
+func (s *serverChanNotifier) Notify(ctx context.Context, event Event) error {
+	endpoint := fmt.Sprintf("https://sctapi.ftqq.com/%s.send", s.conf.SendKey)
+	form := url.Values{}
+	form.Set("title", eventTitle(event))
+	form.Set("desp", strings.ReplaceAll(eventBody(event), "\n", "\n\n"))
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	bodyText, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	var result serverChanResponse
+	if err := json.Unmarshal(bodyText, &result); err != nil {
+		return err
+	}
+	if result.Code != 0 {
+		return errors.New(result.Message)
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/smtp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SMTP 存储通过邮件发送通知所需的配置信息
+type SMTP struct {
+	Host     string   `yaml:"host"`
+	Port     int      `yaml:"port"`
+	Username string   `yaml:"username"`
+	Password string   `yaml:"password"`
+	From     string   `yaml:"from"`
+	To       []string `yaml:"to"`
+}
+
+// smtpNotifier 通过 SMTP 发送邮件通知
+type smtpNotifier struct {
+	conf SMTP
+}
+
+func init() {
+	registerNotifierType("smtp", func(node yaml.Node) (Notifier, error) {
+		var conf SMTP
+		if err := node.Decode(&conf); err != nil {
+			return nil, err
+		}
+		return &smtpNotifier{conf: conf}, nil
+	})
+}
+
+// Notify 把事件渲染成一封纯文本邮件并通过 SMTP 发出
+func (s *smtpNotifier) Notify(ctx context.Context, event Event) error {
+	addr := fmt.Sprintf("%s:%d", s.conf.Host, s.conf.Port)
+	auth := smtp.PlainAuth("", s.conf.Username, s.conf.Password, s.conf.Host)
+	subject := eventTitle(event)
+	msg := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: %s\r\n\r\n%s",
+		s.conf.From, strings.Join(s.conf.To, ","), subject, eventBody(event))
+	return smtp.SendMail(addr, auth, s.conf.From, s.conf.To, []byte(msg))
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Telegram 存储 Telegram Bot 的配置信息
+type Telegram struct {
+	BotToken string `yaml:"bot_token"`
+	ChatID   string `yaml:"chat_id"`
+}
+
+// telegramResponse 是 Telegram Bot API sendMessage 的响应结构
+type telegramResponse struct {
+	OK          bool   `json:"ok"`
+	Description string `json:"description"`
+}
+
+// telegramNotifier 通过 Telegram Bot API 发送通知
+type telegramNotifier struct {
+	conf Telegram
+}
+
+func init() {
+	registerNotifierType("telegram", func(node yaml.Node) (Notifier, error) {
+		var conf Telegram
+		if err := node.Decode(&conf); err != nil {
+			return nil, err
+		}
+		return &telegramNotifier{conf: conf}, nil
+	})
+}
+
+// Notify 调用 Telegram Bot API 的 sendMessage，以 HTML 格式发送事件
+func (t *telegramNotifier) Notify(ctx context.Context, event Event) error {
+	endpoint := fmt.Sprintf("https://api.telegram.org/bot%s/sendMessage", t.conf.BotToken)
+	text := "<b>" + eventTitle(event) + "</b>\n" + eventBody(event)
+	form := url.Values{}
+	form.Set("chat_id", t.conf.ChatID)
+	form.Set("text", text)
+	form.Set("parse_mode", "HTML")
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	bodyText, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	var result telegramResponse
+	if err := json.Unmarshal(bodyText, &result); err != nil {
+		return err
+	}
+	if !result.OK {
+		return fmt.Errorf("telegram: %s", result.Description)
+	}
+	return nil
+}
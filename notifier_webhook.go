@@ -0,0 +1,90 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Webhook 存储通用 JSON webhook 的配置信息
+type Webhook struct {
+	URL      string            `yaml:"url"`
+	Method   string            `yaml:"method"`   // 默认 POST
+	Headers  map[string]string `yaml:"headers"`  // 自定义请求头
+	Template string            `yaml:"template"` // 请求体模板，text/template 语法，可引用 .Title/.Body/.URL/.Kind
+}
+
+// webhookNotifier 把事件按用户定义的模板渲染后 POST 给任意 HTTP 端点
+type webhookNotifier struct {
+	conf Webhook
+	tmpl *template.Template
+}
+
+// webhookTemplateData 是 webhook 模板可以访问的字段
+type webhookTemplateData struct {
+	URL   string
+	Kind  string
+	Title string
+	Body  string
+}
+
+// jsonTemplateValue 把模板里的字段编码成合法的 JSON 字面量（含外层引号），
+// 供模板直接拼进请求体，避免 Body 里的换行、引号破坏默认的 JSON 模板
+func jsonTemplateValue(v string) (string, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+func init() {
+	registerNotifierType("webhook", func(node yaml.Node) (Notifier, error) {
+		var conf Webhook
+		if err := node.Decode(&conf); err != nil {
+			return nil, err
+		}
+		if conf.Method == "" {
+			conf.Method = "POST"
+		}
+		if conf.Template == "" {
+			conf.Template = `{"url":{{.URL | json}},"kind":{{.Kind | json}},"title":{{.Title | json}},"body":{{.Body | json}}}`
+		}
+		tmpl, err := template.New("webhook").Funcs(template.FuncMap{"json": jsonTemplateValue}).Parse(conf.Template)
+		if err != nil {
+			return nil, err
+		}
+		return &webhookNotifier{conf: conf, tmpl: tmpl}, nil
+	})
+}
+
+// Notify 渲染配置的模板并把结果作为请求体发送给 webhook 地址
+func (w *webhookNotifier) Notify(ctx context.Context, event Event) error {
+	var buf bytes.Buffer
+	data := webhookTemplateData{URL: event.URL, Kind: string(event.Kind), Title: eventTitle(event), Body: eventBody(event)}
+	if err := w.tmpl.Execute(&buf, data); err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, w.conf.Method, w.conf.URL, &buf)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range w.conf.Headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook: 推送失败，状态码 %d", resp.StatusCode)
+	}
+	return nil
+}
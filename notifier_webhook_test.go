@@ -0,0 +1,21 @@
+package main
+
+import "testing"
+
+func TestJSONTemplateValue(t *testing.T) {
+	cases := map[string]string{
+		`hello`:        `"hello"`,
+		"line1\nline2": `"line1\nline2"`,
+		`has "quotes"`: `"has \"quotes\""`,
+		"":             `""`,
+	}
+	for in, want := range cases {
+		got, err := jsonTemplateValue(in)
+		if err != nil {
+			t.Fatalf("jsonTemplateValue(%q)返回了错误: %v", in, err)
+		}
+		if got != want {
+			t.Fatalf("jsonTemplateValue(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// EventKind 描述一次监测事件的类型
+type EventKind string
+
+const (
+	EventOffline EventKind = "offline" // 站点无法访问
+	EventTimeout EventKind = "timeout" // 访问超时
+	EventChanged EventKind = "changed" // 页面内容发生变化
+)
+
+// Event 描述一次需要推送给各通知渠道的监测事件
+type Event struct {
+	URL           string
+	Kind          EventKind
+	Timestamp     time.Time
+	Duration      time.Duration
+	Diff          string
+	Screenshot    string
+	SlowResources []resourceEntry
+	Err           error
+}
+
+// Notifier 是所有通知渠道都需要实现的接口
+type Notifier interface {
+	Notify(ctx context.Context, event Event) error
+}
+
+// notifierFactory 根据该渠道在 YAML 中的配置节点构造一个 Notifier 实例
+type notifierFactory func(node yaml.Node) (Notifier, error)
+
+// notifierFactories 是按渠道类型（type 字段）索引的全局注册表，
+// 各内置渠道在各自文件的 init() 中注册自己
+var notifierFactories = map[string]notifierFactory{}
+
+// registerNotifierType 将一种通知渠道类型注册到全局工厂表
+func registerNotifierType(typeName string, factory notifierFactory) {
+	notifierFactories[typeName] = factory
+}
+
+// notifierSpec 是 notifiers 配置节点的通用外壳，用于先识别 type 再做具体解码
+type notifierSpec struct {
+	Type string `yaml:"type"`
+}
+
+// buildNotifiers 把 config.Notifiers 中按名字配置的渠道实例化为 Notifier
+func buildNotifiers(raw map[string]yaml.Node) (map[string]Notifier, error) {
+	result := make(map[string]Notifier, len(raw))
+	for name, node := range raw {
+		var spec notifierSpec
+		if err := node.Decode(&spec); err != nil {
+			return nil, fmt.Errorf("notifiers.%s: %w", name, err)
+		}
+		factory, ok := notifierFactories[spec.Type]
+		if !ok {
+			return nil, fmt.Errorf("notifiers.%s: 未知的通知渠道类型 %q", name, spec.Type)
+		}
+		n, err := factory(node)
+		if err != nil {
+			return nil, fmt.Errorf("notifiers.%s: %w", name, err)
+		}
+		result[name] = n
+	}
+	return result, nil
+}
+
+// resolveNotifiers 把具名的通知渠道分配给每个被监测的 Target：
+// 优先使用该 Target 自己声明的 notifiers 列表，否则回退到旧版的全局 pushplus 配置
+func resolveNotifiers(cfg Config, targets []Target) (map[string][]Notifier, error) {
+	named, err := buildNotifiers(cfg.Notifiers)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := named["pushplus"]; !ok && cfg.Pushplus.Token != "" {
+		named["pushplus"] = newPushplusNotifier(cfg.Pushplus)
+	}
+
+	result := make(map[string][]Notifier, len(targets))
+	for _, target := range targets {
+		names := target.Notifiers
+		if len(names) == 0 {
+			if _, ok := named["pushplus"]; ok {
+				names = []string{"pushplus"}
+			}
+		}
+		notifiers := make([]Notifier, 0, len(names))
+		for _, name := range names {
+			n, ok := named[name]
+			if !ok {
+				return nil, fmt.Errorf("url %s: 未定义的通知渠道 %q", target.URL, name)
+			}
+			notifiers = append(notifiers, n)
+		}
+		result[target.URL] = notifiers
+	}
+	return result, nil
+}
+
+// dispatchEvent 并发地把一次事件发送给该 URL 配置的所有通知渠道；
+// 单个渠道的失败（含重试耗尽）不会影响其它渠道，但会计入 notify_failures 指标
+func dispatchEvent(ctx context.Context, notifiers []Notifier, event Event, metrics *metricsRegistry) {
+	var wg sync.WaitGroup
+	for _, n := range notifiers {
+		wg.Add(1)
+		go func(n Notifier) {
+			defer wg.Done()
+			if err := notifyWithRetry(ctx, n, event); err != nil {
+				log.Println("notify: " + event.URL + " " + err.Error())
+				metrics.incNotifyFailure()
+			}
+		}(n)
+	}
+	wg.Wait()
+}
+
+// notifyWithRetry 对单个渠道的发送做固定次数的指数退避重试
+func notifyWithRetry(ctx context.Context, n Notifier, event Event) error {
+	const maxAttempts = 3
+	backoff := 500 * time.Millisecond
+	var err error
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-time.After(backoff):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+			backoff *= 2
+		}
+		if err = n.Notify(ctx, event); err == nil {
+			return nil
+		}
+	}
+	return err
+}
+
+// eventTitle 渲染事件的通用中文标题，供各通知渠道复用
+func eventTitle(event Event) string {
+	switch event.Kind {
+	case EventOffline:
+		return event.URL + " 网站无法访问!"
+	case EventTimeout:
+		return event.URL + " 网站超时访问!"
+	case EventChanged:
+		return event.URL + " 页面内容发生变化!"
+	default:
+		return event.URL + " 发生未知事件"
+	}
+}
+
+// eventBody 渲染事件的通用纯文本正文，供各通知渠道复用
+func eventBody(event Event) string {
+	body := "事件时间: " + event.Timestamp.Format("2006-01-02 15:04:05")
+	switch event.Kind {
+	case EventOffline:
+		body += "\n错误代码: " + event.Err.Error()
+	case EventTimeout:
+		body += "\n访问时间: " + fmt.Sprintf("%.2fs", event.Duration.Seconds())
+	case EventChanged:
+		body += "\n内容差异:\n" + event.Diff
+	}
+	if event.Screenshot != "" {
+		body += "\n截图: " + event.Screenshot
+	}
+	if len(event.SlowResources) > 0 {
+		body += "\n最慢的子资源:"
+		for _, r := range event.SlowResources {
+			body += fmt.Sprintf("\n- [%d] %s (%dms, %dB)", r.Status, r.URL, r.Duration.Milliseconds(), r.Size)
+		}
+	}
+	return body
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func decodeNotifiersYAML(t *testing.T, src string) map[string]yaml.Node {
+	t.Helper()
+	var raw struct {
+		Notifiers map[string]yaml.Node `yaml:"notifiers"`
+	}
+	if err := yaml.Unmarshal([]byte(src), &raw); err != nil {
+		t.Fatalf("解析测试用YAML失败: %v", err)
+	}
+	return raw.Notifiers
+}
+
+func TestResolveNotifiersLegacyPushplusFallback(t *testing.T) {
+	cfg := Config{Pushplus: Pushplus{Token: "tok"}}
+	targets := []Target{{URL: "https://a.example.com"}}
+
+	result, err := resolveNotifiers(cfg, targets)
+	if err != nil {
+		t.Fatalf("resolveNotifiers返回了错误: %v", err)
+	}
+
+	notifiers := result["https://a.example.com"]
+	if len(notifiers) != 1 {
+		t.Fatalf("未声明notifiers的target应回退到唯一的pushplus渠道，实际得到 %d 个", len(notifiers))
+	}
+	if _, ok := notifiers[0].(*pushplusNotifier); !ok {
+		t.Fatalf("回退渠道应为pushplusNotifier，实际为 %T", notifiers[0])
+	}
+}
+
+func TestResolveNotifiersNoPushplusFallbackWithoutToken(t *testing.T) {
+	cfg := Config{}
+	targets := []Target{{URL: "https://a.example.com"}}
+
+	result, err := resolveNotifiers(cfg, targets)
+	if err != nil {
+		t.Fatalf("resolveNotifiers返回了错误: %v", err)
+	}
+	if len(result["https://a.example.com"]) != 0 {
+		t.Fatal("未配置pushplus token时不应凭空生成回退渠道")
+	}
+}
+
+func TestResolveNotifiersExplicitOverridesLegacyFallback(t *testing.T) {
+	notifiersYAML := `
+notifiers:
+  custom:
+    type: webhook
+    url: https://hooks.example.com/x
+`
+	cfg := Config{
+		Pushplus:  Pushplus{Token: "tok"},
+		Notifiers: decodeNotifiersYAML(t, notifiersYAML),
+	}
+	targets := []Target{{URL: "https://a.example.com", Notifiers: []string{"custom"}}}
+
+	result, err := resolveNotifiers(cfg, targets)
+	if err != nil {
+		t.Fatalf("resolveNotifiers返回了错误: %v", err)
+	}
+	notifiers := result["https://a.example.com"]
+	if len(notifiers) != 1 {
+		t.Fatalf("显式声明了notifiers的target不应叠加pushplus回退，实际得到 %d 个", len(notifiers))
+	}
+	if _, ok := notifiers[0].(*webhookNotifier); !ok {
+		t.Fatalf("显式声明的渠道应为webhookNotifier，实际为 %T", notifiers[0])
+	}
+}
+
+func TestResolveNotifiersUnknownNameIsError(t *testing.T) {
+	cfg := Config{}
+	targets := []Target{{URL: "https://a.example.com", Notifiers: []string{"does-not-exist"}}}
+
+	if _, err := resolveNotifiers(cfg, targets); err == nil {
+		t.Fatal("引用未定义的通知渠道应当返回错误")
+	}
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"context"
+	"log"
+	"math/rand"
+	"strconv"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"github.com/sirupsen/logrus"
+)
+
+// maxJitter 是每次调度触发后、真正执行检查前随机等待的上限，用来打散同一周期内的请求，
+// 避免大量 URL 共用同一个 schedule 时对目标站点和本地浏览器池造成惊群
+const maxJitter = 5 * time.Second
+
+// defaultPollingSeconds 是旧版 url 列表未配置（或配置了非正数）polling 间隔时的回退值，
+// 避免生成 "@every 0s" 这样会让浏览器池忙等的调度
+const defaultPollingSeconds = 60
+
+// Target 描述一个被监测页面完整的轮询策略：地址、内容比对规则、调度周期、超时和重试策略。
+// 不配置 targets 时，会从旧版的 url/polling 配置里自动生成等价的 Target 列表
+type Target struct {
+	URL          string      `yaml:"url"`
+	Schedule     string      `yaml:"schedule"` // cron 表达式，如 "@every 30s" 或 "0 */5 * * * *"
+	Timeout      int         `yaml:"timeout"`  // 超过该秒数视为访问超时，0 表示使用全局 config.timeout
+	Selector     string      `yaml:"selector"`
+	IncludeRegex string      `yaml:"include_regex"`
+	ExcludeRegex string      `yaml:"exclude_regex"`
+	Notifiers    []string    `yaml:"notifiers"`
+	Retry        RetryPolicy `yaml:"retry"`
+}
+
+// RetryPolicy 描述单次轮询失败后的重试次数与重试间隔
+type RetryPolicy struct {
+	MaxAttempts int `yaml:"max_attempts"` // 0/1 表示不重试
+	Backoff     int `yaml:"backoff"`      // 单位秒
+}
+
+// urlEntry 把 Target 里与内容比对相关的字段转换成 extractContent/applyFilters 使用的 URLEntry
+func (t Target) urlEntry() URLEntry {
+	return URLEntry{
+		URL:          t.URL,
+		Selector:     t.Selector,
+		IncludeRegex: t.IncludeRegex,
+		ExcludeRegex: t.ExcludeRegex,
+		Notifiers:    t.Notifiers,
+	}
+}
+
+// alertTimeout 返回用于判断访问是否超时的阈值：Target 自己声明的优先，否则回退到全局配置
+func (t Target) alertTimeout() int {
+	if t.Timeout > 0 {
+		return t.Timeout
+	}
+	return config.Timeout
+}
+
+// resolveTargets 优先使用 targets 配置；如果没有配置 targets，则从旧版的 url 列表和全局
+// polling 间隔生成等价的 Target 列表，从而保持向后兼容
+func resolveTargets(cfg Config) []Target {
+	if len(cfg.Targets) > 0 {
+		return cfg.Targets
+	}
+	polling := cfg.Polling
+	if polling <= 0 {
+		log.Printf("scheduler: polling 未配置或非法 (%d)，回退到默认值 %ds", cfg.Polling, defaultPollingSeconds)
+		polling = defaultPollingSeconds
+	}
+	targets := make([]Target, 0, len(cfg.Url))
+	schedule := "@every " + strconv.Itoa(polling) + "s"
+	for _, entry := range cfg.Url {
+		targets = append(targets, Target{
+			URL:          entry.URL,
+			Schedule:     schedule,
+			Selector:     entry.Selector,
+			IncludeRegex: entry.IncludeRegex,
+			ExcludeRegex: entry.ExcludeRegex,
+			Notifiers:    entry.Notifiers,
+		})
+	}
+	return targets
+}
+
+// startScheduler 为每个 Target 按其各自的 schedule 注册一个 cron 任务。所有任务共享同一个
+// browserPool，借此把并发抓取的标签页数量限制在固定上限内；每次检查的结果同时写入状态存储、
+// 计入指标，并通过 WebSocket hub 推送给仪表盘。由于浏览器是共享的，单个任务里任何一次
+// panic 都不应该带崩整个进程影响其它 target，因此用 cron.Recover 兜底
+func startScheduler(targets []Target, store *contentStore, pool *browserPool, urlNotifiers map[string][]Notifier, status *statusStore, metrics *metricsRegistry, hub *wsHub, checks *checkStore, checkLogger *logrus.Logger) *cron.Cron {
+	c := cron.New(cron.WithSeconds(), cron.WithChain(cron.Recover(cron.DefaultLogger)))
+	for _, target := range targets {
+		target := target
+		_, err := c.AddFunc(target.Schedule, func() {
+			time.Sleep(time.Duration(rand.Int63n(int64(maxJitter))))
+			runCheck(target, store, pool, urlNotifiers[target.URL], status, metrics, hub, checks, checkLogger)
+		})
+		if err != nil {
+			log.Println("scheduler: " + target.URL + " " + err.Error())
+		}
+	}
+	c.Start()
+	return c
+}
+
+// runCheck 执行一次（必要时带重试的）轮询，更新该 URL 在仪表盘上的状态和指标，
+// 把结果写入结构化日志和运行历史数据库，并把结果转换成事件并发分发给该 Target 配置的通知渠道
+func runCheck(target Target, store *contentStore, pool *browserPool, notifiers []Notifier, status *statusStore, metrics *metricsRegistry, hub *wsHub, checks *checkStore, checkLogger *logrus.Logger) {
+	attempts := target.Retry.MaxAttempts
+	if attempts <= 0 {
+		attempts = 1
+	}
+	backoff := time.Duration(target.Retry.Backoff) * time.Second
+
+	var result monitorResult
+	for attempt := 0; attempt < attempts; attempt++ {
+		if attempt > 0 {
+			time.Sleep(backoff)
+		}
+		result = pageMonitor(target, store, pool)
+		if result.Err == nil {
+			break
+		}
+	}
+
+	metrics.incPoll(target.URL)
+	if result.Err == nil {
+		metrics.observeLatency(target.URL, result.Duration.Seconds())
+	}
+
+	kind := statusUp
+	errMsg := ""
+	switch {
+	case result.Err != nil:
+		kind = statusDown
+		errMsg = result.Err.Error()
+	case result.Duration.Seconds() > float64(target.alertTimeout()):
+		kind = statusTimeout
+	case result.Changed:
+		kind = statusChanged
+	}
+	logCheckResult(checkLogger, target.URL, result.Duration, kind, errMsg)
+	if err := checks.record(target.URL, time.Now(), result.Duration, kind, errMsg, result.ContentHash); err != nil {
+		log.Println("checkStore: " + target.URL + " " + err.Error())
+	}
+
+	snapshot := status.update(target.URL, kind, result.Duration, errMsg, result.Screenshot)
+	hub.broadcast(snapshot)
+
+	ctx := context.Background()
+	if result.Err != nil {
+		dispatchEvent(ctx, notifiers, Event{URL: target.URL, Kind: EventOffline, Timestamp: time.Now(), Err: result.Err, Screenshot: result.Screenshot, SlowResources: result.SlowResources}, metrics)
+		return
+	}
+	if result.Duration.Seconds() > float64(target.alertTimeout()) {
+		dispatchEvent(ctx, notifiers, Event{URL: target.URL, Kind: EventTimeout, Timestamp: time.Now(), Duration: result.Duration, Screenshot: result.Screenshot, SlowResources: result.SlowResources}, metrics)
+	}
+	if result.Changed {
+		dispatchEvent(ctx, notifiers, Event{URL: target.URL, Kind: EventChanged, Timestamp: time.Now(), Diff: result.Diff, Screenshot: result.Screenshot, SlowResources: result.SlowResources}, metrics)
+	}
+}
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/boltdb/bolt"
+	"github.com/go-rod/rod"
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// snapshotDBFile 是内容指纹持久化存储的默认文件名
+const snapshotDBFile = "snapshots.db"
+
+// snapshotBucket 是 BoltDB 中保存页面内容快照的 bucket 名称
+const snapshotBucket = "snapshots"
+
+// snapshotRecord 是持久化到 BoltDB 的一条页面内容快照
+type snapshotRecord struct {
+	Hash string `json:"hash"`
+	Text string `json:"text"`
+}
+
+// contentStore 封装了基于 BoltDB 的页面内容指纹存储，按 URL 建索引
+type contentStore struct {
+	db *bolt.DB
+}
+
+// openContentStore 打开（或创建）本地的内容指纹存储
+func openContentStore(path string) (*contentStore, error) {
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, err
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists([]byte(snapshotBucket))
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &contentStore{db: db}, nil
+}
+
+// close 关闭底层的 BoltDB 连接
+func (s *contentStore) close() error {
+	return s.db.Close()
+}
+
+// get 读取某个 URL 上一次保存的内容快照
+func (s *contentStore) get(url string) (snapshotRecord, bool, error) {
+	var record snapshotRecord
+	found := false
+	err := s.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(snapshotBucket))
+		v := b.Get([]byte(url))
+		if v == nil {
+			return nil
+		}
+		found = true
+		return json.Unmarshal(v, &record)
+	})
+	return record, found, err
+}
+
+// set 保存某个 URL 最新的内容快照
+func (s *contentStore) set(url string, record snapshotRecord) error {
+	v, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(snapshotBucket))
+		return b.Put([]byte(url), v)
+	})
+}
+
+// reset 清空已保存的所有页面内容快照
+func (s *contentStore) reset() error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		if err := tx.DeleteBucket([]byte(snapshotBucket)); err != nil && err != bolt.ErrBucketNotFound {
+			return err
+		}
+		_, err := tx.CreateBucket([]byte(snapshotBucket))
+		return err
+	})
+}
+
+// extractContent 从页面中提取用于比对的文本：若配置了 selector 则只取该子树的文本，
+// 否则取整个 body 的渲染文本。page.Element 在找不到节点时会一直重试，调用方必须传入
+// 一个带超时的 page（如 page.Timeout(...) 的克隆），否则 selector 写错或页面改版会让
+// 这次查找无限期挂起，占满浏览器池的标签页
+func extractContent(page *rod.Page, entry URLEntry) (string, error) {
+	if entry.Selector != "" {
+		el, err := page.Element(entry.Selector)
+		if err != nil {
+			return "", err
+		}
+		return el.Text()
+	}
+	body, err := page.Element("body")
+	if err != nil {
+		return "", err
+	}
+	return body.Text()
+}
+
+// applyFilters 依次应用 include_regex / exclude_regex，剔除时间戳、CSRF token 等噪声
+func applyFilters(text string, entry URLEntry) (string, error) {
+	if entry.IncludeRegex != "" {
+		re, err := regexp.Compile(entry.IncludeRegex)
+		if err != nil {
+			return "", err
+		}
+		text = strings.Join(re.FindAllString(text, -1), "\n")
+	}
+	if entry.ExcludeRegex != "" {
+		re, err := regexp.Compile(entry.ExcludeRegex)
+		if err != nil {
+			return "", err
+		}
+		text = re.ReplaceAllString(text, "")
+	}
+	return text, nil
+}
+
+// fingerprint 计算归一化后页面内容的 SHA-256 指纹
+func fingerprint(text string) string {
+	sum := sha256.Sum256([]byte(text))
+	return hex.EncodeToString(sum[:])
+}
+
+// unifiedDiff 生成旧内容到新内容的统一 diff 文本
+func unifiedDiff(url, oldText, newText string) (string, error) {
+	diff := difflib.UnifiedDiff{
+		A:        difflib.SplitLines(oldText),
+		B:        difflib.SplitLines(newText),
+		FromFile: url + " (旧)",
+		ToFile:   url + " (新)",
+		Context:  3,
+	}
+	return difflib.GetUnifiedDiffString(diff)
+}
+
+// detectContentChange 提取当前页面内容、与上次保存的快照比较指纹，
+// 如果发生变化则返回统一 diff 文本，并将最新内容写回存储。无论是否变化都会返回本次内容的指纹，
+// 供调用方写入运行历史
+func detectContentChange(page *rod.Page, entry URLEntry, store *contentStore) (bool, string, string, error) {
+	text, err := extractContent(page, entry)
+	if err != nil {
+		return false, "", "", err
+	}
+	text, err = applyFilters(text, entry)
+	if err != nil {
+		return false, "", "", err
+	}
+
+	newHash := fingerprint(text)
+	previous, existed, err := store.get(entry.URL)
+	if err != nil {
+		return false, "", newHash, err
+	}
+
+	if err := store.set(entry.URL, snapshotRecord{Hash: newHash, Text: text}); err != nil {
+		return false, "", newHash, err
+	}
+
+	if !existed || previous.Hash == newHash {
+		return false, "", newHash, nil
+	}
+
+	diff, err := unifiedDiff(entry.URL, previous.Text, text)
+	if err != nil {
+		return false, "", newHash, err
+	}
+	return true, diff, newHash, nil
+}
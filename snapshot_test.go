@@ -0,0 +1,35 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFingerprint(t *testing.T) {
+	if fingerprint("hello") != fingerprint("hello") {
+		t.Fatal("fingerprint应当对相同输入返回相同结果")
+	}
+	if fingerprint("hello") == fingerprint("world") {
+		t.Fatal("fingerprint应当对不同输入返回不同结果")
+	}
+}
+
+func TestUnifiedDiffNoChange(t *testing.T) {
+	diff, err := unifiedDiff("https://example.com", "same\n", "same\n")
+	if err != nil {
+		t.Fatalf("unifiedDiff返回了错误: %v", err)
+	}
+	if diff != "" {
+		t.Fatalf("内容未变化时diff应为空，实际为: %q", diff)
+	}
+}
+
+func TestUnifiedDiffChange(t *testing.T) {
+	diff, err := unifiedDiff("https://example.com", "old line\n", "new line\n")
+	if err != nil {
+		t.Fatalf("unifiedDiff返回了错误: %v", err)
+	}
+	if !strings.Contains(diff, "-old line") || !strings.Contains(diff, "+new line") {
+		t.Fatalf("diff应包含新旧两行内容，实际为: %q", diff)
+	}
+}
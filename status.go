@@ -0,0 +1,100 @@
+package main
+
+import (
+	"sync"
+	"time"
+)
+
+// statusKind 描述仪表盘上某个 URL 的当前状态
+type statusKind string
+
+const (
+	statusUp      statusKind = "up"
+	statusDown    statusKind = "down"
+	statusTimeout statusKind = "timeout"
+	statusChanged statusKind = "changed"
+)
+
+// historySize 是每个 URL 在内存中保留的历史采样点数量，用于仪表盘的延迟走势图
+const historySize = 50
+
+// historySample 是某一次轮询的延迟采样点
+type historySample struct {
+	Timestamp time.Time `json:"ts"`
+	LatencyMs float64   `json:"latency_ms"`
+}
+
+// targetStatus 是仪表盘展示的某个 URL 的最新状态快照
+type targetStatus struct {
+	URL            string          `json:"url"`
+	Status         statusKind      `json:"status"`
+	LastError      string          `json:"last_error,omitempty"`
+	LastLatencyMs  float64         `json:"last_latency_ms"`
+	LastScreenshot string          `json:"last_screenshot,omitempty"`
+	UpdatedAt      time.Time       `json:"updated_at"`
+	History        []historySample `json:"history"`
+}
+
+// statusStore 在内存中维护每个被监测 URL 的最新状态，供 HTTP API 和 WebSocket 推送读取
+type statusStore struct {
+	mu   sync.RWMutex
+	data map[string]*targetStatus
+}
+
+// snapshot 返回 t 的一份深拷贝，History 也拷贝到新的底层数组，
+// 避免调用方在锁外持有的副本与后续 update() 里的 append/reslice 共享底层数组
+func (t *targetStatus) snapshot() targetStatus {
+	cp := *t
+	cp.History = append([]historySample(nil), t.History...)
+	return cp
+}
+
+// newStatusStore 创建一个空的状态存储
+func newStatusStore() *statusStore {
+	return &statusStore{data: make(map[string]*targetStatus)}
+}
+
+// update 记录一次轮询结果，并返回更新后的状态快照的副本
+func (s *statusStore) update(url string, kind statusKind, latency time.Duration, errMsg, screenshot string) targetStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t, ok := s.data[url]
+	if !ok {
+		t = &targetStatus{URL: url}
+		s.data[url] = t
+	}
+	t.Status = kind
+	t.LastError = errMsg
+	t.LastLatencyMs = float64(latency.Milliseconds())
+	t.UpdatedAt = time.Now()
+	if screenshot != "" {
+		t.LastScreenshot = screenshot
+	}
+	t.History = append(t.History, historySample{Timestamp: t.UpdatedAt, LatencyMs: t.LastLatencyMs})
+	if len(t.History) > historySize {
+		t.History = t.History[len(t.History)-historySize:]
+	}
+	return t.snapshot()
+}
+
+// list 返回当前所有 URL 的状态快照
+func (s *statusStore) list() []targetStatus {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	result := make([]targetStatus, 0, len(s.data))
+	for _, t := range s.data {
+		result = append(result, t.snapshot())
+	}
+	return result
+}
+
+// get 返回单个 URL 的状态快照
+func (s *statusStore) get(url string) (targetStatus, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	t, ok := s.data[url]
+	if !ok {
+		return targetStatus{}, false
+	}
+	return t.snapshot(), true
+}
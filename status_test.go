@@ -0,0 +1,40 @@
+package main
+
+import "testing"
+
+func TestStatusStoreSnapshotIsolation(t *testing.T) {
+	store := newStatusStore()
+	snap := store.update("https://example.com", statusUp, 0, "", "")
+
+	// 修改调用方持有的快照不应影响存储内部状态
+	snap.History[0].LatencyMs = 999
+
+	next, ok := store.get("https://example.com")
+	if !ok {
+		t.Fatal("get应当能找到刚写入的URL")
+	}
+	if next.History[0].LatencyMs == 999 {
+		t.Fatal("snapshot返回的History与内部存储共享了底层数组")
+	}
+
+	// 继续写入不应让之前返回的快照的History发生变化（append/reslice复用底层数组）
+	for i := 0; i < historySize; i++ {
+		store.update("https://example.com", statusUp, 0, "", "")
+	}
+	if len(snap.History) != 1 || snap.History[0].LatencyMs != 999 {
+		t.Fatal("后续update污染了此前返回的快照")
+	}
+}
+
+func TestStatusStoreListIsolation(t *testing.T) {
+	store := newStatusStore()
+	store.update("https://example.com", statusUp, 0, "", "")
+
+	list := store.list()
+	list[0].History[0].LatencyMs = 12345
+
+	again := store.list()
+	if again[0].History[0].LatencyMs == 12345 {
+		t.Fatal("list返回的History与内部存储共享了底层数组")
+	}
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"database/sql"
+	"sort"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// Storage 存储运行历史持久化所使用的数据库配置
+type Storage struct {
+	Driver string `yaml:"driver"` // 目前仅支持 sqlite3
+	DSN    string `yaml:"dsn"`
+}
+
+const defaultStorageDSN = "pagemonitor.db"
+
+// checkStore 把每次巡检结果写入 SQLite，供仪表盘计算可用率、延迟分位数和 MTTR
+type checkStore struct {
+	db *sql.DB
+}
+
+// openCheckStore 打开（或创建）运行历史数据库，并确保 checks 表存在
+func openCheckStore(conf Storage) (*checkStore, error) {
+	dsn := conf.DSN
+	if dsn == "" {
+		dsn = defaultStorageDSN
+	}
+	db, err := sql.Open("sqlite3", dsn)
+	if err != nil {
+		return nil, err
+	}
+	_, err = db.Exec(`CREATE TABLE IF NOT EXISTS checks (
+		url TEXT NOT NULL,
+		ts DATETIME NOT NULL,
+		duration_ms INTEGER NOT NULL,
+		status TEXT NOT NULL,
+		err TEXT,
+		content_hash TEXT
+	)`)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &checkStore{db: db}, nil
+}
+
+// close 关闭底层的数据库连接
+func (c *checkStore) close() error {
+	return c.db.Close()
+}
+
+// record 保存一次巡检结果
+func (c *checkStore) record(url string, ts time.Time, duration time.Duration, status statusKind, errMsg, contentHash string) error {
+	_, err := c.db.Exec(
+		`INSERT INTO checks (url, ts, duration_ms, status, err, content_hash) VALUES (?, ?, ?, ?, ?, ?)`,
+		url, ts, duration.Milliseconds(), string(status), errMsg, contentHash,
+	)
+	return err
+}
+
+// Stats 汇总了某个 URL 在一个时间窗口内的可用率、延迟分位数和平均故障恢复时间 (MTTR)
+type Stats struct {
+	URL         string  `json:"url"`
+	Samples     int     `json:"samples"`
+	UptimePct   float64 `json:"uptime_pct"`
+	P50Ms       float64 `json:"p50_ms"`
+	P95Ms       float64 `json:"p95_ms"`
+	MTTRSeconds float64 `json:"mttr_seconds"`
+}
+
+// stats 计算某个 URL 自 since 以来的可用率、延迟分位数和 MTTR
+func (c *checkStore) stats(url string, since time.Time) (Stats, error) {
+	rows, err := c.db.Query(
+		`SELECT ts, duration_ms, status FROM checks WHERE url = ? AND ts >= ? ORDER BY ts ASC`,
+		url, since,
+	)
+	if err != nil {
+		return Stats{}, err
+	}
+	defer rows.Close()
+
+	var latencies []float64
+	var up, total int
+	var downSince *time.Time
+	var mttrTotal time.Duration
+	var mttrCount int
+
+	for rows.Next() {
+		var ts time.Time
+		var durationMs int64
+		var status string
+		if err := rows.Scan(&ts, &durationMs, &status); err != nil {
+			return Stats{}, err
+		}
+		total++
+		if status == string(statusUp) || status == string(statusChanged) {
+			up++
+			latencies = append(latencies, float64(durationMs))
+			if downSince != nil {
+				mttrTotal += ts.Sub(*downSince)
+				mttrCount++
+				downSince = nil
+			}
+		} else if downSince == nil {
+			t := ts
+			downSince = &t
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return Stats{}, err
+	}
+
+	sort.Float64s(latencies)
+	result := Stats{URL: url, Samples: total}
+	if total > 0 {
+		result.UptimePct = float64(up) / float64(total) * 100
+	}
+	if len(latencies) > 0 {
+		result.P50Ms = percentile(latencies, 0.50)
+		result.P95Ms = percentile(latencies, 0.95)
+	}
+	if mttrCount > 0 {
+		result.MTTRSeconds = mttrTotal.Seconds() / float64(mttrCount)
+	}
+	return result, nil
+}
+
+// percentile 对已排序的样本求分位数（最近邻法）
+func percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	idx := int(p*float64(len(sorted)-1) + 0.5)
+	return sorted[idx]
+}
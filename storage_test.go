@@ -0,0 +1,26 @@
+package main
+
+import "testing"
+
+func TestPercentile(t *testing.T) {
+	sorted := []float64{10, 20, 30, 40, 50}
+	cases := []struct {
+		p    float64
+		want float64
+	}{
+		{0, 10},
+		{0.5, 30},
+		{1, 50},
+	}
+	for _, c := range cases {
+		if got := percentile(sorted, c.p); got != c.want {
+			t.Fatalf("percentile(%v, %v) = %v, want %v", sorted, c.p, got, c.want)
+		}
+	}
+}
+
+func TestPercentileEmpty(t *testing.T) {
+	if got := percentile(nil, 0.5); got != 0 {
+		t.Fatalf("percentile(nil, 0.5) = %v, want 0", got)
+	}
+}
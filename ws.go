@@ -0,0 +1,105 @@
+package main
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// wsChannel 是与单个已连接客户端关联的发送缓冲区；发送缓冲区写满时直接丢弃消息，
+// 保证一个慢客户端不会拖慢其它客户端的事件广播
+type wsChannel struct {
+	conn *websocket.Conn
+	send chan []byte
+}
+
+// wsHub 按 sid 索引所有已连接的 WebSocket 客户端，负责事件的 fan-out 广播
+type wsHub struct {
+	mu       sync.RWMutex
+	clients  map[string]*wsChannel
+	upgrader websocket.Upgrader
+}
+
+// newWSHub 创建一个空的 WebSocket 客户端集合
+func newWSHub() *wsHub {
+	return &wsHub{
+		clients: make(map[string]*wsChannel),
+		upgrader: websocket.Upgrader{
+			ReadBufferSize:  1024,
+			WriteBufferSize: 1024,
+			CheckOrigin:     func(r *http.Request) bool { return true },
+		},
+	}
+}
+
+// serveWS 把一次 HTTP 请求升级为 WebSocket 连接，注册到 hub 中直至连接断开
+func (h *wsHub) serveWS(w http.ResponseWriter, r *http.Request) {
+	conn, err := h.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		log.Println("ws: upgrade " + err.Error())
+		return
+	}
+	sid := r.RemoteAddr + "-" + strconv.FormatInt(time.Now().UnixNano(), 36)
+	ch := &wsChannel{conn: conn, send: make(chan []byte, 32)}
+
+	h.mu.Lock()
+	h.clients[sid] = ch
+	h.mu.Unlock()
+
+	go h.writePump(sid, ch)
+	h.readPump(sid, ch)
+}
+
+// readPump 只是用来检测客户端断开连接；仪表盘目前不需要接收客户端消息
+func (h *wsHub) readPump(sid string, ch *wsChannel) {
+	defer h.remove(sid)
+	for {
+		if _, _, err := ch.conn.ReadMessage(); err != nil {
+			return
+		}
+	}
+}
+
+// writePump 把 send 缓冲区里的消息依次写回给客户端
+func (h *wsHub) writePump(sid string, ch *wsChannel) {
+	defer ch.conn.Close()
+	for msg := range ch.send {
+		if err := ch.conn.WriteMessage(websocket.TextMessage, msg); err != nil {
+			return
+		}
+	}
+}
+
+// remove 把客户端从 hub 中摘除并关闭其发送缓冲区
+func (h *wsHub) remove(sid string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if ch, ok := h.clients[sid]; ok {
+		close(ch.send)
+		delete(h.clients, sid)
+	}
+}
+
+// broadcast 把一个事件序列化为 JSON 并发送给所有已连接客户端；
+// 某个客户端的缓冲区已满时直接丢弃这条消息，不阻塞其它客户端
+func (h *wsHub) broadcast(v interface{}) {
+	msg, err := json.Marshal(v)
+	if err != nil {
+		log.Println("ws: marshal " + err.Error())
+		return
+	}
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	for sid, ch := range h.clients {
+		select {
+		case ch.send <- msg:
+		default:
+			log.Println("ws: client " + sid + " 发送缓冲区已满，丢弃本次消息")
+		}
+	}
+}